@@ -0,0 +1,74 @@
+// Copyright 2021 Siôn le Roux.  All rights reserved.
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package assets
+
+import "testing"
+
+// newTestSheet returns a sprite sheet with a single three-frame "walk" tag,
+// one tick per frame, for exercising Animation without loading real assets
+func newTestSheet(direction string) *SpriteSheet {
+	return &SpriteSheet{
+		Sprite: Frames{
+			{Duration: 1, Position: FramePosition{X: 0, W: 1, H: 1}},
+			{Duration: 1, Position: FramePosition{X: 1, W: 1, H: 1}},
+			{Duration: 1, Position: FramePosition{X: 2, W: 1, H: 1}},
+		},
+		Meta: SpriteMeta{
+			FrameTags: []FrameTags{
+				{Name: "walk", From: 0, To: 2, Direction: direction},
+			},
+		},
+	}
+}
+
+func TestAnimationLoop(t *testing.T) {
+	a := NewAnimation(newTestSheet("forward"), "walk", true)
+
+	if a.Frame != 0 {
+		t.Fatalf("starting frame = %d, want 0", a.Frame)
+	}
+
+	for i := 0; i < 3; i++ {
+		a.Update()
+	}
+
+	if a.Frame != 0 {
+		t.Errorf("frame after wrapping past the last frame = %d, want 0", a.Frame)
+	}
+	if a.Done {
+		t.Errorf("a looping animation should never latch Done")
+	}
+}
+
+func TestAnimationOneShot(t *testing.T) {
+	a := NewAnimation(newTestSheet("forward"), "walk", false)
+
+	for i := 0; i < 5; i++ {
+		a.Update()
+	}
+
+	if !a.Done {
+		t.Fatalf("one-shot animation should be Done once its last frame has played out")
+	}
+	if a.Frame != 2 {
+		t.Errorf("frame after Done = %d, want 2 (held on the last frame)", a.Frame)
+	}
+}
+
+func TestAnimationReverse(t *testing.T) {
+	a := NewAnimation(newTestSheet("reverse"), "walk", true)
+
+	if a.Frame != 2 {
+		t.Fatalf("starting frame for a reverse tag = %d, want 2 (its last frame)", a.Frame)
+	}
+
+	for i := 0; i < 3; i++ {
+		a.Update()
+	}
+
+	if a.Frame != 2 {
+		t.Errorf("frame after wrapping past the first frame = %d, want 2", a.Frame)
+	}
+}
@@ -7,6 +7,7 @@ package assets
 import (
 	"embed"
 	"encoding/json"
+	"image"
 	"image/png"
 	"io/ioutil"
 	"log"
@@ -65,9 +66,9 @@ type SpriteSheet struct {
 	Image  *ebiten.Image
 }
 
-// Load a sprite image and associated meta-data given a file name (without
-// extension)
-func loadSprite(name string) *SpriteSheet {
+// LoadSprite loads a sprite image and associated meta-data given a file name
+// (without extension)
+func LoadSprite(name string) *SpriteSheet {
 	name = path.Join("assets", "sprites", name)
 	log.Printf("loading %s\n", name)
 
@@ -83,8 +84,7 @@ func loadSprite(name string) *SpriteSheet {
 	}
 
 	var ss SpriteSheet
-	json.Unmarshal(data, &ss)
-	if err != nil {
+	if err := json.Unmarshal(data, &ss); err != nil {
 		log.Fatal(err)
 	}
 
@@ -93,6 +93,96 @@ func loadSprite(name string) *SpriteSheet {
 	return &ss
 }
 
+// findTag looks up a named tag in a sprite sheet, falling back to a tag
+// covering every frame if the sheet has no tag with that name
+func findTag(sheet *SpriteSheet, name string) FrameTags {
+	for _, t := range sheet.Meta.FrameTags {
+		if t.Name == name {
+			return t
+		}
+	}
+	return FrameTags{Name: name, From: 0, To: len(sheet.Sprite) - 1}
+}
+
+// Animation plays back the frames of a tagged sequence from a SpriteSheet,
+// advancing frames in step with each Frame's own duration
+type Animation struct {
+	Sheet *SpriteSheet
+	Tag   FrameTags
+	Frame int
+	Loop  bool
+	Done  bool
+
+	elapsed int
+}
+
+// NewAnimation creates an Animation that plays the tag called name from
+// sheet. Tags with the Aseprite "reverse" direction play from their last
+// frame back to their first; every other direction plays front to back. If
+// loop is false the animation stops and holds on its last frame instead of
+// repeating, and Done is set to true once that last frame's duration has
+// elapsed.
+func NewAnimation(sheet *SpriteSheet, name string, loop bool) *Animation {
+	tag := findTag(sheet, name)
+
+	a := &Animation{Sheet: sheet, Tag: tag, Loop: loop}
+	if tag.Direction == "reverse" {
+		a.Frame = tag.To
+	} else {
+		a.Frame = tag.From
+	}
+
+	return a
+}
+
+// Update advances the animation by one tick, moving to the next frame once
+// the current frame's duration has elapsed
+func (a *Animation) Update() {
+	if a.Done {
+		return
+	}
+
+	a.elapsed++
+	if a.elapsed < a.Sheet.Sprite[a.Frame].Duration {
+		return
+	}
+	a.elapsed = 0
+
+	reverse := a.Tag.Direction == "reverse"
+	if reverse {
+		a.Frame--
+	} else {
+		a.Frame++
+	}
+
+	if reverse && a.Frame < a.Tag.From {
+		a.Frame = a.Tag.To
+	} else if !reverse && a.Frame > a.Tag.To {
+		a.Frame = a.Tag.From
+	} else {
+		return
+	}
+
+	if !a.Loop {
+		if reverse {
+			a.Frame = a.Tag.From
+		} else {
+			a.Frame = a.Tag.To
+		}
+		a.Done = true
+	}
+}
+
+// Draw renders the animation's current frame at the given screen coordinates
+func (a *Animation) Draw(screen *ebiten.Image, x, y float64) {
+	f := a.Sheet.Sprite[a.Frame].Position
+	frame := a.Sheet.Image.SubImage(image.Rect(f.X, f.Y, f.X+f.W, f.Y+f.H)).(*ebiten.Image)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(x, y)
+	screen.DrawImage(frame, op)
+}
+
 // Load an image from embedded FS into an ebiten Image object
 func LoadImage(name string) *ebiten.Image {
 	log.Printf("loading %s\n", name)
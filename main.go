@@ -6,28 +6,88 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"image"
 	"log"
-	"math/rand"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/sinisterstuf/freefall/assets"
 	"github.com/sinisterstuf/freefall/nokia"
+	"github.com/tinne26/etxt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
 )
 
+// sampleRate is the audio sample rate used throughout the game, it has to be
+// the same for every sound or they'll play back at the wrong speed
+const sampleRate = 44100
+
+// defaultVolume is the master volume new sounds start out at
+const defaultVolume = 0.5
+
+// startingAltitude is how much altitude the player starts a run with; the
+// game ends once it reaches zero
+const startingAltitude = 1000
+
 func main() {
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for the random number generator")
+	flag.Parse()
+
 	windowScale := 10
 	ebiten.SetWindowSize(nokia.GameSize.X*windowScale, nokia.GameSize.Y*windowScale)
 	ebiten.SetWindowTitle("Freefall")
 
+	physics := NewPhysics(*seed)
+
+	audioContext := audio.NewContext(sampleRate)
+
+	music := &assets.Sound{}
+	music.AddMusic("music", sampleRate, audioContext)
+
+	chuteSound := &assets.Sound{}
+	chuteSound.AddSound("parachute", sampleRate, audioContext)
+
+	windSound := &assets.Sound{}
+	windSound.AddMusic("wind", sampleRate, audioContext)
+
+	dustSound := &assets.Sound{}
+	dustSound.AddSound("dust", sampleRate, audioContext)
+
+	playerSheet := assets.LoadSprite("player")
+	dustSheet := assets.LoadSprite("dust")
+
+	font := assets.LoadFont("nokia.ttf")
+	renderer := etxt.NewStdRenderer()
+	renderer.SetFont(font)
+	renderer.SetSizePx(6)
+
 	game := &Game{
 		Size: nokia.GameSize,
 		Player: &Player{
 			Coords: image.Pt(nokia.GameSize.X/2, nokia.GameSize.Y/2),
+			Sheet:  playerSheet,
+			Anim:   assets.NewAnimation(playerSheet, "falling", true),
 		},
-		Dusts: Dusts{},
+		DustSheet: dustSheet,
+		Physics:   physics,
+		Altitude:  startingAltitude,
+
+		Audio:      audioContext,
+		Music:      music,
+		ChuteSound: chuteSound,
+		WindSound:  windSound,
+		DustSound:  dustSound,
+		Volume:     defaultVolume,
+
+		Font:     font,
+		Renderer: renderer,
+		Printer:  message.NewPrinter(language.English),
 	}
+	game.SetVolume(defaultVolume)
+	game.SetScene(TitleScene{})
 
 	if err := ebiten.RunGame(game); err != nil {
 		log.Fatal(err)
@@ -36,10 +96,86 @@ func main() {
 
 // Game represents the main game state
 type Game struct {
-	Size   image.Point
-	Player *Player
-	Dusts  Dusts
-	Tick   int64
+	Size      image.Point
+	Player    *Player
+	DustSheet *assets.SpriteSheet
+	Physics   *Physics
+	Tick      int64
+
+	// Entities holds everything the current scene updates and draws each
+	// frame, in draw order
+	Entities []Entity
+
+	CurrentScene Scene
+
+	Audio      *audio.Context
+	Music      *assets.Sound
+	ChuteSound *assets.Sound
+	WindSound  *assets.Sound
+	DustSound  *assets.Sound
+	Volume     float64
+
+	// SoundQueue batches sounds requested during Update so several entities
+	// can ask for playback in the same tick without talking over each other
+	SoundQueue assets.Sounds
+
+	Altitude float64
+	Font     *etxt.Font
+	Renderer *etxt.Renderer
+	Printer  *message.Printer
+}
+
+// AddEntity appends an entity to the scene's ordered list
+func (g *Game) AddEntity(e Entity) {
+	g.Entities = append(g.Entities, e)
+}
+
+// RemoveEntity removes the first occurrence of an entity from the ordered
+// list, if present
+func (g *Game) RemoveEntity(e Entity) {
+	for i, existing := range g.Entities {
+		if existing == e {
+			g.Entities = append(g.Entities[:i], g.Entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetScene transitions the game to a new scene
+func (g *Game) SetScene(s Scene) {
+	g.CurrentScene = s
+	s.Enter(g)
+}
+
+// QueueSound marks a sound to be played once the current tick's Update is
+// finished
+func (g *Game) QueueSound(s *assets.Sound) {
+	g.SoundQueue = append(g.SoundQueue, s)
+}
+
+// flushSoundQueue plays every sound queued up during Update and empties the
+// queue ready for the next tick
+func (g *Game) flushSoundQueue() {
+	for _, s := range g.SoundQueue {
+		s.Play()
+	}
+	g.SoundQueue = g.SoundQueue[:0]
+}
+
+// SetVolume sets the master volume for every sound in the game
+func (g *Game) SetVolume(v float64) {
+	if v < 0 {
+		v = 0
+	}
+	if v > 1 {
+		v = 1
+	}
+	g.Volume = v
+
+	g.Music.SetVolume(v)
+	g.ChuteSound.SetVolume(v)
+	g.WindSound.SetVolume(v)
+	g.DustSound.SetVolume(v)
 }
 
 // Layout is hardcoded for now, may be made dynamic in future
@@ -65,88 +201,161 @@ func (g *Game) Update() error {
 		}
 	}
 
-	if g.Player.Chute {
-		if g.Tick%2 == 0 {
-			g.Dusts.Update()
-		}
-	} else {
-		g.Dusts.Update()
+	// Pressing - and = adjusts the master volume
+	if inpututil.IsKeyJustPressed(ebiten.KeyMinus) {
+		g.SetVolume(g.Volume - 0.1)
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEqual) {
+		g.SetVolume(g.Volume + 0.1)
 	}
 
-	// Movement controls
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		g.Player.Pull()
+	return g.CurrentScene.Update(g)
+}
+
+// Restart resets gameplay state back to the start of a run
+func (g *Game) Restart() {
+	g.Tick = 0
+	g.Altitude = startingAltitude
+
+	g.Player.Coords = image.Pt(g.Size.X/2, g.Size.Y/2)
+	g.Player.Chute = false
+	g.Player.Velocity = 0
+	g.Player.Anim = assets.NewAnimation(g.Player.Sheet, "falling", true)
+
+	g.Entities = []Entity{g.Player}
+}
+
+// spawnDust adds a new dust entity at the bottom of the screen as long as
+// fewer than maxDusts are currently alive
+func (g *Game) spawnDust() {
+	const maxDusts = 5
+
+	count := 0
+	for _, e := range g.Entities {
+		if _, ok := e.(*Dust); ok {
+			count++
+		}
+	}
+	if count >= maxDusts {
+		return
 	}
 
-	return nil
+	dsX := g.Physics.Rand.Intn(nokia.GameSize.X)
+	g.AddEntity(&Dust{
+		X:    dsX,
+		Y:    float64(nokia.GameSize.Y + 1),
+		Anim: assets.NewAnimation(g.DustSheet, "falling", true),
+	})
 }
 
 // Draw draws the game screen by one frame
 func (g *Game) Draw(screen *ebiten.Image) {
-	screen.Fill(nokia.PaletteOriginal.Dark())
+	g.CurrentScene.Draw(g, screen)
+}
 
-	for _, d := range g.Dusts {
-		ebitenutil.DrawRect(
-			screen,
-			float64(d.Coords.X), float64(d.Coords.Y),
-			1, 1,
-			nokia.PaletteOriginal.Light(),
-		)
+// drawEntities draws every entity in Entities, with the player drawn last
+// so it's never occluded by dust or anything else drawn over it
+func (g *Game) drawEntities(screen *ebiten.Image) {
+	for _, e := range g.Entities {
+		if e == g.Player {
+			continue
+		}
+		e.Draw(screen)
 	}
+	g.Player.Draw(screen)
+}
 
-	ebitenutil.DrawRect(
-		screen,
-		float64(g.Player.Coords.X),
-		float64(g.Player.Coords.Y),
-		5,
-		5,
-		nokia.PaletteOriginal.Light(),
-	)
+// drawHUD renders the altitude score in the corner of the screen
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	g.Renderer.SetTarget(screen)
+	g.Renderer.SetColor(nokia.PaletteOriginal.Light())
+	g.Renderer.SetAlign(etxt.Top, etxt.Left)
+	g.Renderer.Draw(g.Printer.Sprintf("%d", int(g.Altitude)), 1, 1)
 }
 
 // Player is the player character in the game
 type Player struct {
-	Coords image.Point
-	Chute  bool
+	Coords   image.Point
+	Chute    bool
+	Velocity float64
+	Sheet    *assets.SpriteSheet
+	Anim     *assets.Animation
 }
 
-// Move moves the player upwards
+// Pull toggles the player's parachute
 func (p *Player) Pull() {
 	p.Chute = !p.Chute
+	if p.Chute {
+		p.Anim = assets.NewAnimation(p.Sheet, "parachute-open", false)
+	} else {
+		p.Anim = assets.NewAnimation(p.Sheet, "falling", true)
+	}
 }
 
-// Dust is decorative dirt on the screen to give the illusion of motion
-type Dust struct {
-	Coords image.Point
+// Update advances the player's animation and eases its altitude Velocity
+// towards the target for its current parachute state, switching from the
+// one-shot parachute-open animation to the looping parachute-deployed one
+// once it finishes
+func (p *Player) Update(g *Game) error {
+	p.Anim.Update()
+	if p.Chute && p.Anim.Done {
+		p.Anim = assets.NewAnimation(p.Sheet, "parachute-deployed", true)
+	}
+
+	target := fallSpeed
+	if p.Chute {
+		target = chuteSpeed
+	}
+	p.Velocity = Ease(p.Velocity, target, accel)
+
+	return nil
 }
 
-func (d *Dust) Update() {
-	// Move dusts up
-	log.Println(d.Coords.X, d.Coords.Y)
-	d.Coords.Y--
-	log.Println(d.Coords.X, d.Coords.Y)
+// Draw draws the player at its current animation frame
+func (p *Player) Draw(screen *ebiten.Image) {
+	p.Anim.Draw(screen, float64(p.Coords.X), float64(p.Coords.Y))
 }
 
-type Dusts []*Dust
+// Dust is decorative dirt on the screen to give the illusion of motion
+type Dust struct {
+	X        int
+	Y        float64
+	Velocity float64
+	Anim     *assets.Animation
+}
 
-func (ds *Dusts) Update() {
-	const maxDusts = 5
+// Update eases the dust's Velocity towards the target speed for chute, then
+// moves it up the screen by that Velocity. Once it drifts off the top of the
+// screen it queues its despawn sound; the scene is responsible for actually
+// removing it from Game.Entities once every entity has had its turn
+func (d *Dust) Update(g *Game) error {
+	if d.Y < 0 {
+		return nil
+	}
 
-	if len(*ds) < maxDusts {
-		dsX := rand.Intn(nokia.GameSize.X)
-		*ds = append(*ds, &Dust{
-			image.Pt(dsX, nokia.GameSize.Y+1),
-		})
+	target := dustFallSpeed
+	if g.Player.Chute {
+		target = dustChuteSpeed
 	}
+	d.Velocity = Ease(d.Velocity, target, accel)
+	d.Y += d.Velocity
 
-	for i, d := range *ds {
-		d.Update()
-		if d.Coords.Y < 0 {
-			*ds = append((*ds)[:i], (*ds)[i+1:]...)
-		}
+	if d.Y < 0 {
+		g.QueueSound(g.DustSound)
+		return nil
 	}
+
+	d.Anim.Update()
+	return nil
+}
+
+// Draw draws the dust at its current animation frame
+func (d *Dust) Draw(screen *ebiten.Image) {
+	d.Anim.Draw(screen, float64(d.X), d.Y)
 }
 
+// Entity is anything a Scene updates and draws every frame
 type Entity interface {
-	Update()
+	Update(*Game) error
+	Draw(*ebiten.Image)
 }
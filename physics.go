@@ -0,0 +1,60 @@
+// Copyright 2021 Siôn le Roux.  All rights reserved.
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"math/rand"
+)
+
+// Fall speeds in pixels per tick for entities moving under gravity and
+// under a deployed parachute, used as the targets Ease settles towards
+const (
+	fallSpeed  = 1.0
+	chuteSpeed = 0.2
+)
+
+// dustFallSpeed and dustChuteSpeed are the same, but for the dust, which
+// moves in the opposite direction (up the screen) to suggest the player's
+// own motion
+const (
+	dustFallSpeed  = -1.0
+	dustChuteSpeed = -0.3
+)
+
+// accel is how quickly Velocity eases towards its target speed each tick
+const accel = 0.05
+
+// Physics is the subsystem that owns randomness and motion for the game's
+// falling entities
+type Physics struct {
+	Rand *rand.Rand
+}
+
+// NewPhysics creates a Physics subsystem seeded with seed. The seed is
+// logged so that a particular run can be reproduced later by passing it
+// back in with the -seed flag
+func NewPhysics(seed int64) *Physics {
+	log.Printf("random seed: %d\n", seed)
+	return &Physics{Rand: rand.New(rand.NewSource(seed))}
+}
+
+// Ease moves velocity one step towards target, giving smooth acceleration
+// and deceleration instead of snapping straight to the new speed
+func Ease(velocity, target, step float64) float64 {
+	switch {
+	case velocity < target:
+		velocity += step
+		if velocity > target {
+			velocity = target
+		}
+	case velocity > target:
+		velocity -= step
+		if velocity < target {
+			velocity = target
+		}
+	}
+	return velocity
+}
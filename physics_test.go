@@ -0,0 +1,32 @@
+// Copyright 2021 Siôn le Roux.  All rights reserved.
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestEase(t *testing.T) {
+	cases := []struct {
+		name     string
+		velocity float64
+		target   float64
+		step     float64
+		want     float64
+	}{
+		{"approaches target from below", 0, 1, 0.3, 0.3},
+		{"approaches target from above", 1, 0, 0.3, 0.7},
+		{"clamps at target when the step would overshoot it", 0.9, 1, 0.3, 1},
+		{"clamps at target when the step would undershoot it", 0.1, 0, 0.3, 0},
+		{"leaves velocity alone once it's already at target", 0.5, 0.5, 0.3, 0.5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Ease(c.velocity, c.target, c.step)
+			if got != c.want {
+				t.Errorf("Ease(%v, %v, %v) = %v, want %v", c.velocity, c.target, c.step, got, c.want)
+			}
+		})
+	}
+}
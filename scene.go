@@ -0,0 +1,143 @@
+// Copyright 2021 Siôn le Roux.  All rights reserved.
+// Use of this source code is subject to an MIT-style
+// licence which can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/sinisterstuf/freefall/nokia"
+	"github.com/tinne26/etxt"
+)
+
+// Scene is one distinct state of the game (title screen, active gameplay,
+// game over), each owning its own input handling, update logic and drawing
+type Scene interface {
+	Enter(g *Game)
+	Update(g *Game) error
+	Draw(g *Game, screen *ebiten.Image)
+}
+
+// TitleScene shows the game's title and waits for the player to start
+type TitleScene struct{}
+
+// Enter does nothing; the title screen has no state of its own
+func (TitleScene) Enter(g *Game) {}
+
+// Update starts a run once the player presses space
+func (TitleScene) Update(g *Game) error {
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.SetScene(PlayScene{})
+	}
+	return nil
+}
+
+// Draw shows the game's title and a prompt to start
+func (TitleScene) Draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(nokia.PaletteOriginal.Dark())
+
+	g.Renderer.SetTarget(screen)
+	g.Renderer.SetColor(nokia.PaletteOriginal.Light())
+	g.Renderer.SetAlign(etxt.YCenter, etxt.XCenter)
+	g.Renderer.Draw("FREEFALL", g.Size.X/2, g.Size.Y/2-4)
+	g.Renderer.Draw("press SPACE to start", g.Size.X/2, g.Size.Y/2+4)
+}
+
+// PlayScene is active gameplay: the player falling, dust drifting past and
+// the altitude countdown that ends the run
+type PlayScene struct{}
+
+// Enter resets gameplay state and starts the music and wind/fall loop for
+// the new run
+func (PlayScene) Enter(g *Game) {
+	g.Restart()
+	g.Music.Play()
+	g.WindSound.Play()
+}
+
+// Update spawns dust, advances every entity, handles the parachute toggle
+// and ends the run once altitude runs out
+func (PlayScene) Update(g *Game) error {
+	g.spawnDust()
+
+	for _, e := range g.Entities {
+		if err := e.Update(g); err != nil {
+			return err
+		}
+	}
+
+	// Remove dust that has drifted off the top of the screen; collecting
+	// the expired entities first avoids mutating Entities while the range
+	// above is still reading it
+	var expired []Entity
+	for _, e := range g.Entities {
+		if d, ok := e.(*Dust); ok && d.Y < 0 {
+			expired = append(expired, e)
+		}
+	}
+	for _, e := range expired {
+		g.RemoveEntity(e)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		g.Player.Pull()
+		if g.Player.Chute {
+			g.QueueSound(g.ChuteSound)
+			g.WindSound.Pause()
+		} else {
+			g.WindSound.Play()
+		}
+	}
+
+	// The player burns altitude in free-fall and sheds it slowly once the
+	// parachute is deployed, until there's none left and the run ends
+	g.Altitude -= g.Player.Velocity
+	if g.Altitude <= 0 {
+		g.Altitude = 0
+		g.SetScene(GameOverScene{})
+	}
+
+	g.flushSoundQueue()
+
+	return nil
+}
+
+// Draw draws every entity, player on top, followed by the HUD
+func (PlayScene) Draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(nokia.PaletteOriginal.Dark())
+
+	g.drawEntities(screen)
+
+	g.drawHUD(screen)
+}
+
+// GameOverScene shows the final state of a run and waits for the player to
+// restart
+type GameOverScene struct{}
+
+// Enter does nothing; the final frame of gameplay is left on screen
+func (GameOverScene) Enter(g *Game) {}
+
+// Update restarts a new run once the player presses R
+func (GameOverScene) Update(g *Game) error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyR) {
+		g.SetScene(PlayScene{})
+	}
+	return nil
+}
+
+// Draw shows the last gameplay frame with a "GAME OVER" overlay on top
+func (GameOverScene) Draw(g *Game, screen *ebiten.Image) {
+	screen.Fill(nokia.PaletteOriginal.Dark())
+
+	g.drawEntities(screen)
+
+	g.drawHUD(screen)
+
+	g.Renderer.SetTarget(screen)
+	g.Renderer.SetColor(nokia.PaletteOriginal.Light())
+	g.Renderer.SetAlign(etxt.YCenter, etxt.XCenter)
+	g.Renderer.Draw("GAME OVER", g.Size.X/2, g.Size.Y/2-4)
+	g.Renderer.Draw("press R to restart", g.Size.X/2, g.Size.Y/2+4)
+}